@@ -0,0 +1,109 @@
+// Command licensegen mints signed go-ai-coder license keys from an
+// ed25519 private key. The matching public key must be embedded in
+// internal/license for the generated keys to verify.
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Bakery-street-project/go-ai-coder/internal/license"
+)
+
+func main() {
+	var (
+		keyPath  = flag.String("private-key", "", "path to a base64-encoded ed25519 private key seed")
+		tier     = flag.String("tier", "pro", "license tier: free, pro, or enterprise")
+		customer = flag.String("customer", "", "customer id to embed in the claims")
+		seats    = flag.Int("seats", 1, "seat count")
+		days     = flag.Int("days", 365, "validity period in days")
+		trial    = flag.Bool("trial", false, "mark the license as a trial")
+		features = flag.String("features", "", "comma-separated feature flags to enable")
+	)
+	flag.Parse()
+
+	if err := run(*keyPath, *tier, *customer, *features, *seats, *days, *trial); err != nil {
+		fmt.Fprintf(os.Stderr, "licensegen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(keyPath, tierFlag, customer, featuresFlag string, seats, days int, trial bool) error {
+	if keyPath == "" {
+		return fmt.Errorf("-private-key is required")
+	}
+
+	priv, err := loadPrivateKey(keyPath)
+	if err != nil {
+		return err
+	}
+
+	tier, err := parseTier(tierFlag)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	claims := license.Claims{
+		CustomerID: customer,
+		Tier:       tier,
+		Seats:      seats,
+		Features:   parseFeatures(featuresFlag),
+		Trial:      trial,
+		IssuedAt:   now,
+		ExpiresAt:  now.Add(time.Duration(days) * 24 * time.Hour),
+	}
+
+	key, err := license.SignClaims(priv, claims)
+	if err != nil {
+		return err
+	}
+	fmt.Println(key)
+	return nil
+}
+
+func loadPrivateKey(path string) (ed25519.PrivateKey, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read private key: %w", err)
+	}
+	seed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(raw)))
+	if err != nil {
+		return nil, fmt.Errorf("decode private key: %w", err)
+	}
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("private key must be a %d-byte ed25519 seed, got %d", ed25519.SeedSize, len(seed))
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+func parseTier(s string) (license.Tier, error) {
+	switch strings.ToLower(s) {
+	case "free":
+		return license.FreeTier, nil
+	case "pro":
+		return license.ProTier, nil
+	case "enterprise":
+		return license.EnterpriseTier, nil
+	default:
+		return 0, fmt.Errorf("unknown tier %q (want free, pro, or enterprise)", s)
+	}
+}
+
+func parseFeatures(s string) map[string]bool {
+	if s == "" {
+		return nil
+	}
+	features := map[string]bool{}
+	for _, f := range strings.Split(s, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			features[f] = true
+		}
+	}
+	return features
+}
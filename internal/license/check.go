@@ -3,7 +3,6 @@ package license
 import (
 	"fmt"
 	"os"
-	"strings"
 	"sync"
 	"time"
 )
@@ -24,14 +23,75 @@ type License struct {
 	DailyRuns int
 	MaxRuns   int
 	LastReset time.Time
-	mu        sync.Mutex
+
+	// ExpiresAt is the zero Time for keys that don't expire (e.g. the
+	// unsigned Free tier fallback).
+	ExpiresAt time.Time
+	Features  map[string]bool
+	Seats     int
+	Trial     bool
+	// TrialEndsAt is the zero Time unless Trial is true.
+	TrialEndsAt time.Time
+
+	// MaxTokens is the per-run token cap; -1 means unlimited.
+	MaxTokens int
+	// MaxConcurrent is the concurrent-run cap; -1 means unlimited. Used by
+	// package limiter.
+	MaxConcurrent int
+
+	// Entitlements holds every capability this license grants, including
+	// the ones MaxRuns/MaxTokens/MaxConcurrent above are derived from. See
+	// Entitled/IntEntitlement/RequireEntitlement.
+	Entitlements map[Entitlement]int
+
+	// limits is the table this license's caps were derived from, so a
+	// later downgrade (e.g. trial expiry) lands on the right Free-tier
+	// caps instead of the package default. Unexported: it doesn't survive
+	// a disk cache round-trip, so loadCachedLicense backfills it with
+	// DefaultLimits.
+	limits LimitsTable
+
+	mu sync.Mutex
 }
 
 var (
 	currentLicense *License
+	stateMu        sync.Mutex
 	once           sync.Once
+	licenseManager *Manager
 )
 
+// setCurrentLicense swaps in l as the active license under stateMu. It's
+// the only way currentLicense is written after Initialize's first call, so
+// Activate/StartTrial/the manager's watcher can all race safely.
+func setCurrentLicense(l *License) {
+	stateMu.Lock()
+	currentLicense = l
+	stateMu.Unlock()
+}
+
+func getCurrentLicense() *License {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	return currentLicense
+}
+
+// setLicenseManager swaps in m as the process-wide manager under stateMu.
+// Like setCurrentLicense, this is what lets Initialize's once.Do, Stop, and
+// every reader (CanRun, Activate, StartTrial) touch licenseManager from
+// different goroutines safely.
+func setLicenseManager(m *Manager) {
+	stateMu.Lock()
+	licenseManager = m
+	stateMu.Unlock()
+}
+
+func getLicenseManager() *Manager {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	return licenseManager
+}
+
 // FreeTierLimits
 const (
 	FreeMaxRunsPerDay   = 5
@@ -42,56 +102,175 @@ const (
 	EnterpriseMaxTokens = -1 // Unlimited
 )
 
-// Initialize checks for LICENSE_KEY and sets up the license
-func Initialize() *License {
+// TierLimits is the set of caps applied to a license of a given tier.
+type TierLimits struct {
+	MaxRuns       int
+	MaxTokens     int
+	MaxConcurrent int
+}
+
+// LimitsTable maps each Tier to its TierLimits. It's injected via Options
+// so tests can exercise arbitrary caps without mutating package globals.
+type LimitsTable struct {
+	Free       TierLimits
+	Pro        TierLimits
+	Enterprise TierLimits
+}
+
+// DefaultLimits is the LimitsTable Initialize uses in production.
+var DefaultLimits = LimitsTable{
+	Free:       TierLimits{MaxRuns: FreeMaxRunsPerDay, MaxTokens: FreeMaxTokensPerRun, MaxConcurrent: 1},
+	Pro:        TierLimits{MaxRuns: ProMaxRunsPerDay, MaxTokens: ProMaxTokensPerRun, MaxConcurrent: 4},
+	Enterprise: TierLimits{MaxRuns: EnterpriseMaxRuns, MaxTokens: EnterpriseMaxTokens, MaxConcurrent: -1},
+}
+
+func (t LimitsTable) forTier(tier Tier) TierLimits {
+	switch tier {
+	case EnterpriseTier:
+		return t.Enterprise
+	case ProTier:
+		return t.Pro
+	default:
+		return t.Free
+	}
+}
+
+// Options configures a License. The zero value reproduces the historical
+// Free-tier, no-key behavior.
+type Options struct {
+	// Key is a signed license key, or "" for Free tier.
+	Key string
+	// Limits overrides DefaultLimits. The zero value uses DefaultLimits.
+	Limits LimitsTable
+}
+
+func (o Options) limits() LimitsTable {
+	if o.Limits == (LimitsTable{}) {
+		return DefaultLimits
+	}
+	return o.Limits
+}
+
+// Initialize checks for LICENSE_KEY and sets up the process-wide license
+// singleton, starting a background Manager that polls LICENSE_ENDPOINT
+// when one is set, and otherwise just watches for local trial expiry.
+// Passing opts bypasses the singleton and environment variables entirely,
+// building and returning a fresh, independent License instead -- this is
+// how tests get an isolated instance without the old resetLicense/sync.Once
+// dance.
+func Initialize(opts ...Options) *License {
+	if len(opts) > 0 {
+		return licenseFromKey(opts[0].Key, opts[0].limits())
+	}
+
 	once.Do(func() {
-		key := os.Getenv("LICENSE_KEY")
-		currentLicense = &License{
-			Key:       key,
-			LastReset: time.Now(),
-		}
+		lic := licenseFromKey(os.Getenv("LICENSE_KEY"), DefaultLimits)
 
-		if key == "" {
-			currentLicense.Tier = FreeTier
-			currentLicense.MaxRuns = FreeMaxRunsPerDay
-			printFreeTierMessage()
-		} else if validateKey(key) {
-			if strings.HasPrefix(key, "ENT_") {
-				currentLicense.Tier = EnterpriseTier
-				currentLicense.MaxRuns = EnterpriseMaxRuns
-				fmt.Println("✅ Enterprise License activated - Unlimited usage")
-			} else {
-				currentLicense.Tier = ProTier
-				currentLicense.MaxRuns = ProMaxRunsPerDay
-				fmt.Println("✅ Pro License activated - 100 runs/day, 10K tokens/run")
+		cfg := ManagerConfig{Endpoint: os.Getenv("LICENSE_ENDPOINT")}.withDefaults()
+
+		// No local key: fall back to the last-known-good cached license
+		// while the manager's first refresh is in flight.
+		if lic.Key == "" && cfg.Endpoint != "" {
+			if cached, err := loadCachedLicense(cfg.CachePath); err == nil {
+				lic = cached
 			}
-		} else {
-			currentLicense.Tier = FreeTier
-			currentLicense.MaxRuns = FreeMaxRunsPerDay
-			fmt.Println("⚠️  Invalid license key. Falling back to Free Tier.")
-			printFreeTierMessage()
 		}
+		setCurrentLicense(lic)
+
+		// Always run the manager, even without a remote endpoint, so a
+		// trial license's expiry is still detected and watchers still
+		// fire on the downgrade to Free.
+		mgr := NewManager(cfg)
+		mgr.Start(lic)
+		mgr.Subscribe(CallbackWatcher{New: setCurrentLicense})
+		setLicenseManager(mgr)
 	})
-	return currentLicense
+	return getCurrentLicense()
 }
 
-// validateKey checks if the license key is valid
-// TODO: Connect to Gumroad/LemonSqueezy API for real validation
-func validateKey(key string) bool {
-	// Stub validation - accepts PRO_ or ENT_ prefixed keys
-	if strings.HasPrefix(key, "PRO_") && len(key) >= 20 {
-		return true
+// Stop shuts down the background license manager started by Initialize. It
+// is a no-op if Initialize has not started one.
+func Stop() {
+	mgr := getLicenseManager()
+	if mgr == nil {
+		return
 	}
-	if strings.HasPrefix(key, "ENT_") && len(key) >= 20 {
-		return true
+	mgr.Stop()
+	setLicenseManager(nil)
+}
+
+// licenseFromKey builds a License from a (possibly empty) LICENSE_KEY value
+// against limits, falling back to Free tier when the key is absent or
+// fails verification, and printing the usual console messaging.
+func licenseFromKey(key string, limits LimitsTable) *License {
+	if key == "" {
+		printFreeTierMessage()
+		return freeLicense(key, limits)
+	}
+
+	claims, err := validateKey(key)
+	if err != nil {
+		fmt.Printf("⚠️  Invalid license key (%v). Falling back to Free Tier.\n", err)
+		printFreeTierMessage()
+		return freeLicense(key, limits)
+	}
+
+	lic := licenseFromClaims(key, claims, limits)
+	switch lic.Tier {
+	case EnterpriseTier:
+		fmt.Println("✅ Enterprise License activated - Unlimited usage")
+	case ProTier:
+		fmt.Println("✅ Pro License activated - 100 runs/day, 10K tokens/run")
+	}
+	return lic
+}
+
+// licenseFromClaims builds a License from already-verified claims, with no
+// console output, so callers that poll silently (e.g. Manager) don't spam
+// the banner on every refresh.
+func licenseFromClaims(key string, claims Claims, limits LimitsTable) *License {
+	ents := DefaultEntitlements(claims.Tier, limits)
+	return &License{
+		Tier:          claims.Tier,
+		Key:           key,
+		LastReset:     time.Now(),
+		ExpiresAt:     claims.ExpiresAt,
+		Features:      claims.Features,
+		Seats:         claims.Seats,
+		Trial:         claims.Trial,
+		MaxRuns:       ents[EntitlementMaxRuns],
+		MaxTokens:     ents[EntitlementMaxTokens],
+		MaxConcurrent: ents[EntitlementMaxConcurrent],
+		Entitlements:  ents,
+		limits:        limits,
+	}
+}
+
+func freeLicense(key string, limits LimitsTable) *License {
+	ents := DefaultEntitlements(FreeTier, limits)
+	return &License{
+		Tier:          FreeTier,
+		Key:           key,
+		LastReset:     time.Now(),
+		MaxRuns:       ents[EntitlementMaxRuns],
+		MaxTokens:     ents[EntitlementMaxTokens],
+		MaxConcurrent: ents[EntitlementMaxConcurrent],
+		Entitlements:  ents,
+		limits:        limits,
 	}
-	return false
 }
 
 // CanRun checks if the user can perform another run
 func (l *License) CanRun() (bool, string) {
 	l.mu.Lock()
-	defer l.mu.Unlock()
+
+	var trialJustExpired bool
+	var before licenseState
+	if l.Trial && !l.TrialEndsAt.IsZero() && time.Now().After(l.TrialEndsAt) {
+		trialJustExpired = true
+		before = l.snapshotLocked()
+		l.downgradeToFreeLocked()
+	}
 
 	// Reset daily counter if new day
 	if time.Since(l.LastReset) > 24*time.Hour {
@@ -99,31 +278,40 @@ func (l *License) CanRun() (bool, string) {
 		l.LastReset = time.Now()
 	}
 
-	// Enterprise has unlimited runs
-	if l.Tier == EnterpriseTier {
+	var ok bool
+	var msg string
+	switch {
+	case l.Tier == EnterpriseTier:
+		// Enterprise has unlimited runs
+		l.DailyRuns++
+		ok = true
+	case l.MaxRuns >= 0 && l.DailyRuns >= l.MaxRuns:
+		msg = fmt.Sprintf("Daily limit reached (%d/%d runs). Upgrade at https://bakerstreetproject221B.store/pricing", l.DailyRuns, l.MaxRuns)
+	default:
 		l.DailyRuns++
-		return true, ""
+		ok = true
 	}
+	l.mu.Unlock()
 
-	// Check limits for Free/Pro
-	if l.DailyRuns >= l.MaxRuns {
-		return false, fmt.Sprintf("Daily limit reached (%d/%d runs). Upgrade at https://bakerstreetproject221B.store/pricing", l.DailyRuns, l.MaxRuns)
+	// The manager's ticker isn't the only thing that can catch a trial
+	// expiring -- CanRun usually gets there first, on the very next run.
+	// Route the downgrade through the manager's own notify path so
+	// subscribed Watchers still see it, instead of silently flipping
+	// Trial/Tier with nobody told.
+	if trialJustExpired {
+		if mgr := getLicenseManager(); mgr != nil {
+			mgr.notifyTrialExpiry(l, before)
+		}
 	}
 
-	l.DailyRuns++
-	return true, ""
+	return ok, msg
 }
 
 // GetMaxTokens returns max tokens allowed per run
 func (l *License) GetMaxTokens() int {
-	switch l.Tier {
-	case EnterpriseTier:
-		return EnterpriseMaxTokens
-	case ProTier:
-		return ProMaxTokensPerRun
-	default:
-		return FreeMaxTokensPerRun
-	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.MaxTokens
 }
 
 // GetTierName returns human-readable tier name
@@ -143,22 +331,59 @@ func (l *License) GetStatus() string {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
+	tierName := l.GetTierName()
+	if l.Trial {
+		tierName = fmt.Sprintf("%s (Trial - %s left)", tierName, daysLeft(l.TrialEndsAt))
+	}
+
+	var status string
 	if l.Tier == EnterpriseTier {
-		return fmt.Sprintf("License: %s | Runs today: %d | Unlimited", l.GetTierName(), l.DailyRuns)
+		status = fmt.Sprintf("License: %s | Runs today: %d | Unlimited", tierName, l.DailyRuns)
+	} else {
+		status = fmt.Sprintf("License: %s | Runs: %d/%d | Tokens/run: %d", tierName, l.DailyRuns, l.MaxRuns, l.MaxTokens)
+	}
+	if !l.Trial && !l.ExpiresAt.IsZero() {
+		status += fmt.Sprintf(" | Expires in %s", formatCountdown(time.Until(l.ExpiresAt)))
+	}
+	return status
+}
+
+// formatCountdown renders a duration until expiry the way GetStatus wants it:
+// whole days once there's at least one, otherwise whole hours, or "expired".
+func formatCountdown(d time.Duration) string {
+	if d <= 0 {
+		return "expired"
 	}
-	return fmt.Sprintf("License: %s | Runs: %d/%d | Tokens/run: %d", l.GetTierName(), l.DailyRuns, l.MaxRuns, l.GetMaxTokens())
+	if days := int(d.Hours() / 24); days >= 1 {
+		return fmt.Sprintf("%d day(s)", days)
+	}
+	return fmt.Sprintf("%d hour(s)", int(d.Hours()))
+}
+
+// HasFeature reports whether the license carries the named feature flag.
+func (l *License) HasFeature(name string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.Features[name]
+}
+
+// IsTrial reports whether this license is a time-boxed trial.
+func (l *License) IsTrial() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.Trial
 }
 
 // GetLicense returns the current license instance
 func GetLicense() *License {
-	if currentLicense == nil {
-		return Initialize()
+	if lic := getCurrentLicense(); lic != nil {
+		return lic
 	}
-	return currentLicense
+	return Initialize()
 }
 
 func printFreeTierMessage() {
-	fmt.Println(`
+	fmt.Print(`
 ╔════════════════════════════════════════════════════════════════╗
 ║                    🔓 FREE TIER ACTIVE                         ║
 ╠════════════════════════════════════════════════════════════════╣
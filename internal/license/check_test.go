@@ -2,43 +2,23 @@ package license
 
 import (
 	"os"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 func resetLicense() {
-	currentLicense = nil
-	once = sync.Once{}
-}
-
-func TestValidateKey(t *testing.T) {
-	tests := []struct {
-		name     string
-		key      string
-		expected bool
-	}{
-		{"Valid Pro Key", "PRO_1234567890123456", true},
-		{"Valid Enterprise Key", "ENT_1234567890123456", true},
-		{"Invalid Prefix", "FREE_1234567890123456", false},
-		{"Too Short Pro", "PRO_123", false},
-		{"Empty Key", "", false},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := validateKey(tt.key)
-			if result != tt.expected {
-				t.Errorf("validateKey(%s) = %v, want %v", tt.key, result, tt.expected)
-			}
-		})
+	if licenseManager != nil {
+		licenseManager.Stop()
 	}
+	setCurrentLicense(nil)
+	licenseManager = nil
+	once = sync.Once{}
 }
 
 func TestFreeTierLimits(t *testing.T) {
-	resetLicense()
-	os.Unsetenv("LICENSE_KEY")
-
-	license := Initialize()
+	license := Initialize(Options{})
 
 	if license.Tier != FreeTier {
 		t.Errorf("Expected FreeTier, got %v", license.Tier)
@@ -60,11 +40,8 @@ func TestFreeTierLimits(t *testing.T) {
 }
 
 func TestProTierLimits(t *testing.T) {
-	resetLicense()
-	os.Setenv("LICENSE_KEY", "PRO_12345678901234567890")
-	defer os.Unsetenv("LICENSE_KEY")
-
-	license := Initialize()
+	key := signedTestKey(t, Claims{Tier: ProTier, ExpiresAt: time.Now().Add(30 * 24 * time.Hour)})
+	license := Initialize(Options{Key: key})
 
 	if license.Tier != ProTier {
 		t.Errorf("Expected ProTier, got %v", license.Tier)
@@ -76,11 +53,8 @@ func TestProTierLimits(t *testing.T) {
 }
 
 func TestEnterpriseTier(t *testing.T) {
-	resetLicense()
-	os.Setenv("LICENSE_KEY", "ENT_12345678901234567890")
-	defer os.Unsetenv("LICENSE_KEY")
-
-	license := Initialize()
+	key := signedTestKey(t, Claims{Tier: EnterpriseTier, ExpiresAt: time.Now().Add(30 * 24 * time.Hour)})
+	license := Initialize(Options{Key: key})
 
 	if license.Tier != EnterpriseTier {
 		t.Errorf("Expected EnterpriseTier, got %v", license.Tier)
@@ -96,6 +70,56 @@ func TestEnterpriseTier(t *testing.T) {
 	}
 }
 
+func TestInitializeWithCustomLimits(t *testing.T) {
+	key := signedTestKey(t, Claims{Tier: ProTier, ExpiresAt: time.Now().Add(30 * 24 * time.Hour)})
+	license := Initialize(Options{
+		Key: key,
+		Limits: LimitsTable{
+			Pro: TierLimits{MaxRuns: 2, MaxTokens: 500, MaxConcurrent: 1},
+		},
+	})
+
+	if license.MaxRuns != 2 || license.MaxTokens != 500 || license.MaxConcurrent != 1 {
+		t.Fatalf("expected injected limits to apply, got MaxRuns=%d MaxTokens=%d MaxConcurrent=%d",
+			license.MaxRuns, license.MaxTokens, license.MaxConcurrent)
+	}
+}
+
+func TestInitializeFromSignedKey(t *testing.T) {
+	resetLicense()
+	t.Cleanup(Stop)
+	key := signedTestKey(t, Claims{
+		Tier:      ProTier,
+		Seats:     5,
+		Features:  map[string]bool{"web-search": true},
+		ExpiresAt: time.Now().Add(30 * 24 * time.Hour),
+	})
+	os.Setenv("LICENSE_KEY", key)
+	defer os.Unsetenv("LICENSE_KEY")
+
+	license := Initialize()
+
+	if license.Tier != ProTier {
+		t.Fatalf("Expected ProTier, got %v", license.Tier)
+	}
+	if license.Seats != 5 {
+		t.Errorf("Expected 5 seats, got %d", license.Seats)
+	}
+	if !license.HasFeature("web-search") {
+		t.Error("Expected web-search feature to be enabled")
+	}
+	if license.HasFeature("multi-agent") {
+		t.Error("multi-agent feature should not be enabled")
+	}
+}
+
+func TestGetStatusExpiryCountdown(t *testing.T) {
+	l := &License{Tier: ProTier, MaxRuns: ProMaxRunsPerDay, ExpiresAt: time.Now().Add(72 * time.Hour)}
+	if status := l.GetStatus(); !strings.Contains(status, "Expires in") || !strings.Contains(status, "day(s)") {
+		t.Errorf("expected expiry countdown in status, got %q", status)
+	}
+}
+
 func TestGetTierName(t *testing.T) {
 	tests := []struct {
 		tier     Tier
@@ -0,0 +1,117 @@
+package license
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Entitlement names a capability or numeric cap a license may grant, e.g.
+// "max_runs" or "web-search". New capabilities are added here instead of
+// touching every call site that used to switch on Tier directly.
+type Entitlement string
+
+// Numeric entitlements back the License.MaxRuns/MaxTokens/MaxConcurrent
+// fields; -1 means unlimited, matching the existing TierLimits convention.
+const (
+	EntitlementMaxRuns       Entitlement = "max_runs"
+	EntitlementMaxTokens     Entitlement = "max_tokens"
+	EntitlementMaxConcurrent Entitlement = "max_concurrent"
+)
+
+// Boolean feature entitlements. A value of 1 means granted, 0 (or absent)
+// means not granted. Signed keys can still grant arbitrary ad-hoc features
+// via Claims.Features/HasFeature; these are the well-known ones that ship
+// with a per-tier default.
+const (
+	EntitlementWebSearch      Entitlement = "web-search"
+	EntitlementMultiAgent     Entitlement = "multi-agent"
+	EntitlementCustomModels   Entitlement = "custom-models"
+	EntitlementSSO            Entitlement = "sso"
+	EntitlementAuditLogExport Entitlement = "audit-log-export"
+)
+
+// defaultFeatureEntitlements is the per-tier default for boolean
+// entitlements. Unlisted tiers/entitlements default to not granted.
+var defaultFeatureEntitlements = map[Tier]map[Entitlement]int{
+	FreeTier: {},
+	ProTier: {
+		EntitlementWebSearch:  1,
+		EntitlementMultiAgent: 1,
+	},
+	EnterpriseTier: {
+		EntitlementWebSearch:      1,
+		EntitlementMultiAgent:     1,
+		EntitlementCustomModels:   1,
+		EntitlementSSO:            1,
+		EntitlementAuditLogExport: 1,
+	},
+}
+
+// DefaultEntitlements builds the entitlement set a license of tier gets out
+// of the box: the numeric caps from limits, plus tier's default feature
+// grants. Callers that need to add customer-specific extras (seats,
+// add-ons) can start from this and overlay their own via MergeEntitlements.
+func DefaultEntitlements(tier Tier, limits LimitsTable) map[Entitlement]int {
+	tl := limits.forTier(tier)
+	ents := map[Entitlement]int{
+		EntitlementMaxRuns:       tl.MaxRuns,
+		EntitlementMaxTokens:     tl.MaxTokens,
+		EntitlementMaxConcurrent: tl.MaxConcurrent,
+	}
+	for e, v := range defaultFeatureEntitlements[tier] {
+		ents[e] = v
+	}
+	return ents
+}
+
+// MergeEntitlements resolves multiple stacked entitlement sets (e.g. a base
+// Pro license plus an add-on) to a single set, taking the maximum value per
+// key so an add-on can only grant capability, never take it away. -1
+// ("unlimited", the same sentinel TierLimits uses) always wins the
+// comparison regardless of which side it's on.
+func MergeEntitlements(tables ...map[Entitlement]int) map[Entitlement]int {
+	merged := make(map[Entitlement]int)
+	for _, t := range tables {
+		for e, v := range t {
+			cur, ok := merged[e]
+			switch {
+			case !ok:
+				merged[e] = v
+			case cur == -1:
+				// already unlimited; nothing can raise it further
+			case v == -1 || v > cur:
+				merged[e] = v
+			}
+		}
+	}
+	return merged
+}
+
+// ErrNotEntitled is returned by RequireEntitlement when the license doesn't
+// carry the requested entitlement.
+var ErrNotEntitled = errors.New("license: not entitled")
+
+// Entitled reports whether the license carries e as a truthy (nonzero)
+// entitlement. Unknown entitlements default to false.
+func (l *License) Entitled(e Entitlement) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.Entitlements[e] != 0
+}
+
+// IntEntitlement returns the numeric value of e, or 0 if the license
+// doesn't carry it.
+func (l *License) IntEntitlement(e Entitlement) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.Entitlements[e]
+}
+
+// RequireEntitlement returns ErrNotEntitled, wrapped with an upgrade
+// prompt, if the license doesn't carry e.
+func (l *License) RequireEntitlement(e Entitlement) error {
+	if l.Entitled(e) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s - upgrade at https://bakerstreetproject221B.store/pricing", ErrNotEntitled, e)
+}
@@ -0,0 +1,92 @@
+package license
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDefaultEntitlementsPerTier(t *testing.T) {
+	tests := []struct {
+		tier     Tier
+		web      bool
+		sso      bool
+		maxTok   int
+	}{
+		{FreeTier, false, false, FreeMaxTokensPerRun},
+		{ProTier, true, false, ProMaxTokensPerRun},
+		{EnterpriseTier, true, true, EnterpriseMaxTokens},
+	}
+
+	for _, tt := range tests {
+		ents := DefaultEntitlements(tt.tier, DefaultLimits)
+		if got := ents[EntitlementWebSearch] != 0; got != tt.web {
+			t.Errorf("tier %v: web-search = %v, want %v", tt.tier, got, tt.web)
+		}
+		if got := ents[EntitlementSSO] != 0; got != tt.sso {
+			t.Errorf("tier %v: sso = %v, want %v", tt.tier, got, tt.sso)
+		}
+		if got := ents[EntitlementMaxTokens]; got != tt.maxTok {
+			t.Errorf("tier %v: max_tokens = %d, want %d", tt.tier, got, tt.maxTok)
+		}
+	}
+}
+
+func TestEntitledUnknownDefaultsFalse(t *testing.T) {
+	l := &License{Entitlements: DefaultEntitlements(FreeTier, DefaultLimits)}
+	if l.Entitled(Entitlement("made-up-feature")) {
+		t.Error("unknown entitlement should default to not entitled")
+	}
+	if got := l.IntEntitlement(Entitlement("made-up-cap")); got != 0 {
+		t.Errorf("IntEntitlement for unknown key = %d, want 0", got)
+	}
+}
+
+func TestRequireEntitlement(t *testing.T) {
+	l := &License{Entitlements: DefaultEntitlements(ProTier, DefaultLimits)}
+	if err := l.RequireEntitlement(EntitlementWebSearch); err != nil {
+		t.Errorf("Pro should have web-search: %v", err)
+	}
+	if err := l.RequireEntitlement(EntitlementSSO); !errors.Is(err, ErrNotEntitled) {
+		t.Errorf("RequireEntitlement(sso) = %v, want ErrNotEntitled", err)
+	}
+}
+
+func TestMergeEntitlementsTakesMaxPerKey(t *testing.T) {
+	base := map[Entitlement]int{
+		EntitlementMaxRuns:   ProMaxRunsPerDay,
+		EntitlementWebSearch: 1,
+		EntitlementSSO:       0,
+	}
+	addOn := map[Entitlement]int{
+		EntitlementMaxRuns:      50, // lower than base; base should win
+		EntitlementSSO:          1,  // add-on grants a feature base doesn't have
+		EntitlementCustomModels: 1,
+	}
+
+	merged := MergeEntitlements(base, addOn)
+
+	if got := merged[EntitlementMaxRuns]; got != ProMaxRunsPerDay {
+		t.Errorf("max_runs = %d, want the larger value %d", got, ProMaxRunsPerDay)
+	}
+	if merged[EntitlementSSO] != 1 {
+		t.Error("sso should be granted after merging in the add-on")
+	}
+	if merged[EntitlementWebSearch] != 1 {
+		t.Error("web-search from base should survive the merge")
+	}
+	if merged[EntitlementCustomModels] != 1 {
+		t.Error("custom-models from the add-on should survive the merge")
+	}
+}
+
+func TestMergeEntitlementsUnlimitedAlwaysWins(t *testing.T) {
+	enterprise := DefaultEntitlements(EnterpriseTier, DefaultLimits) // max_runs == -1
+	addOn := map[Entitlement]int{EntitlementMaxRuns: 50}
+
+	if got := MergeEntitlements(enterprise, addOn)[EntitlementMaxRuns]; got != -1 {
+		t.Errorf("merge(unlimited, 50) = %d, want -1 (unlimited must not be downgraded)", got)
+	}
+	if got := MergeEntitlements(addOn, enterprise)[EntitlementMaxRuns]; got != -1 {
+		t.Errorf("merge(50, unlimited) = %d, want -1 (order shouldn't matter)", got)
+	}
+}
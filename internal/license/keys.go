@@ -0,0 +1,107 @@
+package license
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// publicKeyB64 is the ed25519 public key used to verify signed license keys
+// offline. The matching private key is kept outside this repo and is used
+// only by cmd/licensegen to mint new keys.
+const publicKeyB64 = "rRDTV1ab8JKvvSgehLu2woQqf0kQ5BYo1hFqKIx/WLI="
+
+// publicKey is parsed from publicKeyB64 at init time. Tests override it with
+// a throwaway keypair so they can sign fixtures without the real private key.
+var publicKey ed25519.PublicKey
+
+func init() {
+	raw, err := base64.StdEncoding.DecodeString(publicKeyB64)
+	if err != nil || len(raw) != ed25519.PublicKeySize {
+		panic("license: invalid embedded public key")
+	}
+	publicKey = ed25519.PublicKey(raw)
+}
+
+// Claims are the structured contents of a signed license key.
+type Claims struct {
+	CustomerID string          `json:"customer_id"`
+	Tier       Tier            `json:"tier"`
+	Seats      int             `json:"seats"`
+	Features   map[string]bool `json:"features,omitempty"`
+	Trial      bool            `json:"trial,omitempty"`
+	IssuedAt   time.Time       `json:"issued_at"`
+	ExpiresAt  time.Time       `json:"expires_at"`
+	NotBefore  time.Time       `json:"not_before,omitempty"`
+}
+
+var (
+	// ErrMalformedKey is returned when a license key isn't in the
+	// <base64url(claims)>.<base64url(sig)> format or the claims don't
+	// decode as JSON.
+	ErrMalformedKey = errors.New("license: malformed key")
+	// ErrBadSignature is returned when the signature doesn't verify
+	// against the embedded public key.
+	ErrBadSignature = errors.New("license: signature verification failed")
+	// ErrExpired is returned when the claims' ExpiresAt is in the past.
+	ErrExpired = errors.New("license: license expired")
+	// ErrNotYetValid is returned when the claims' NotBefore is in the future.
+	ErrNotYetValid = errors.New("license: license not yet valid")
+	// ErrUnknownTier is returned when the claims carry a tier this build
+	// doesn't recognize.
+	ErrUnknownTier = errors.New("license: unknown tier")
+)
+
+// SignClaims encodes c as "<base64url(json_claims)>.<base64url(ed25519_sig)>".
+// It's exported for cmd/licensegen, which holds the private key.
+func SignClaims(priv ed25519.PrivateKey, c Claims) (string, error) {
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("license: marshal claims: %w", err)
+	}
+	sig := ed25519.Sign(priv, payload)
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// validateKey parses and verifies a signed license key, rejecting malformed,
+// tampered, expired, or not-yet-valid keys.
+func validateKey(key string) (Claims, error) {
+	parts := strings.SplitN(key, ".", 2)
+	if len(parts) != 2 {
+		return Claims{}, ErrMalformedKey
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return Claims{}, fmt.Errorf("%w: %v", ErrMalformedKey, err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return Claims{}, fmt.Errorf("%w: %v", ErrMalformedKey, err)
+	}
+	if !ed25519.Verify(publicKey, payload, sig) {
+		return Claims{}, ErrBadSignature
+	}
+
+	var c Claims
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return Claims{}, fmt.Errorf("%w: %v", ErrMalformedKey, err)
+	}
+	switch c.Tier {
+	case FreeTier, ProTier, EnterpriseTier:
+	default:
+		return Claims{}, ErrUnknownTier
+	}
+
+	now := time.Now()
+	if !c.NotBefore.IsZero() && now.Before(c.NotBefore) {
+		return Claims{}, ErrNotYetValid
+	}
+	if !c.ExpiresAt.IsZero() && now.After(c.ExpiresAt) {
+		return Claims{}, ErrExpired
+	}
+	return c, nil
+}
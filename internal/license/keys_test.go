@@ -0,0 +1,94 @@
+package license
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"os"
+	"testing"
+	"time"
+)
+
+// testPriv is the private half of the keypair TestMain swaps in for
+// publicKey, so tests can mint valid license keys without the real
+// production private key.
+var testPriv ed25519.PrivateKey
+
+func TestMain(m *testing.M) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	publicKey = pub
+	testPriv = priv
+	os.Exit(m.Run())
+}
+
+// signedTestKey signs c with the test keypair and fails the test on error.
+func signedTestKey(t *testing.T, c Claims) string {
+	t.Helper()
+	key, err := SignClaims(testPriv, c)
+	if err != nil {
+		t.Fatalf("SignClaims: %v", err)
+	}
+	return key
+}
+
+func TestValidateKeyExpired(t *testing.T) {
+	key := signedTestKey(t, Claims{Tier: ProTier, ExpiresAt: time.Now().Add(-time.Hour)})
+	if _, err := validateKey(key); err != ErrExpired {
+		t.Fatalf("validateKey() error = %v, want ErrExpired", err)
+	}
+}
+
+func TestValidateKeyNotYetValid(t *testing.T) {
+	key := signedTestKey(t, Claims{
+		Tier:      ProTier,
+		NotBefore: time.Now().Add(time.Hour),
+		ExpiresAt: time.Now().Add(24 * time.Hour),
+	})
+	if _, err := validateKey(key); err != ErrNotYetValid {
+		t.Fatalf("validateKey() error = %v, want ErrNotYetValid", err)
+	}
+}
+
+func TestValidateKeyTampered(t *testing.T) {
+	key := signedTestKey(t, Claims{Tier: ProTier, ExpiresAt: time.Now().Add(24 * time.Hour)})
+	tampered := key[:len(key)-4] + "aaaa"
+	if _, err := validateKey(tampered); err != ErrBadSignature {
+		t.Fatalf("validateKey() error = %v, want ErrBadSignature", err)
+	}
+}
+
+func TestValidateKeyWrongTier(t *testing.T) {
+	key := signedTestKey(t, Claims{Tier: Tier(99), ExpiresAt: time.Now().Add(24 * time.Hour)})
+	if _, err := validateKey(key); err != ErrUnknownTier {
+		t.Fatalf("validateKey() error = %v, want ErrUnknownTier", err)
+	}
+}
+
+func TestValidateKeyMalformed(t *testing.T) {
+	for _, key := range []string{"", "not-a-license-key", "onlyonepart"} {
+		if _, err := validateKey(key); err == nil {
+			t.Errorf("validateKey(%q) expected error, got nil", key)
+		}
+	}
+}
+
+func TestHasFeatureUnknown(t *testing.T) {
+	l := &License{Features: map[string]bool{"web-search": true}}
+	if !l.HasFeature("web-search") {
+		t.Error("expected known feature to be present")
+	}
+	if l.HasFeature("custom-models") {
+		t.Error("expected unknown feature to be absent")
+	}
+}
+
+func TestIsTrial(t *testing.T) {
+	if (&License{Trial: true}).IsTrial() != true {
+		t.Error("expected IsTrial() to be true")
+	}
+	if (&License{}).IsTrial() != false {
+		t.Error("expected IsTrial() to be false by default")
+	}
+}
@@ -0,0 +1,98 @@
+// Package limiter wraps a Runner with the daily-run, concurrent-run, and
+// per-run token caps carried by a license.License, so callers enforce
+// entitlements once instead of duplicating the gating logic at every call
+// site.
+package limiter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Bakery-street-project/go-ai-coder/internal/license"
+)
+
+// Runner performs a single run under license gating, such as an agent
+// executor or CLI command dispatcher.
+type Runner interface {
+	Run(ctx context.Context, req any) (resp any, err error)
+}
+
+// RunnerFunc adapts a plain function to the Runner interface.
+type RunnerFunc func(ctx context.Context, req any) (any, error)
+
+// Run implements Runner.
+func (f RunnerFunc) Run(ctx context.Context, req any) (any, error) { return f(ctx, req) }
+
+// TokenCounter is implemented by responses that know how many tokens a run
+// consumed. Limit uses it to enforce the license's per-run token cap after
+// the call completes, since usage is often only known once a run finishes.
+type TokenCounter interface {
+	TokensUsed() int
+}
+
+var (
+	// ErrDailyLimit is returned when the license's daily run cap has been reached.
+	ErrDailyLimit = errors.New("limiter: daily run limit reached")
+	// ErrTokenLimit is returned when a completed run exceeded the license's per-run token cap.
+	ErrTokenLimit = errors.New("limiter: per-run token limit exceeded")
+	// ErrConcurrencyLimit is returned when the license's concurrent-run cap is already saturated.
+	ErrConcurrencyLimit = errors.New("limiter: concurrent run limit reached")
+)
+
+type ctxKey int
+
+const maxTokensKey ctxKey = iota
+
+// MaxTokensFromContext returns the per-run token budget Limit attached to
+// ctx, and whether the license enforces one at all (false for unlimited).
+func MaxTokensFromContext(ctx context.Context) (int, bool) {
+	v, ok := ctx.Value(maxTokensKey).(int)
+	return v, ok
+}
+
+// Limit wraps r so every call to Run is gated by l's daily-run,
+// concurrent-run, and per-run token caps.
+func Limit(r Runner, l *license.License) Runner {
+	lr := &limitedRunner{runner: r, license: l}
+	if max := l.MaxConcurrent; max >= 0 {
+		lr.sem = make(chan struct{}, max)
+	}
+	return lr
+}
+
+type limitedRunner struct {
+	runner  Runner
+	license *license.License
+	sem     chan struct{} // nil means no concurrency cap
+}
+
+// Run implements Runner.
+func (lr *limitedRunner) Run(ctx context.Context, req any) (any, error) {
+	if lr.sem != nil {
+		select {
+		case lr.sem <- struct{}{}:
+			defer func() { <-lr.sem }()
+		default:
+			return nil, ErrConcurrencyLimit
+		}
+	}
+
+	if ok, msg := lr.license.CanRun(); !ok {
+		return nil, fmt.Errorf("%w: %s", ErrDailyLimit, msg)
+	}
+
+	maxTokens := lr.license.GetMaxTokens()
+	if maxTokens >= 0 {
+		ctx = context.WithValue(ctx, maxTokensKey, maxTokens)
+	}
+
+	resp, err := lr.runner.Run(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+	if tc, ok := resp.(TokenCounter); ok && maxTokens >= 0 && tc.TokensUsed() > maxTokens {
+		return resp, fmt.Errorf("%w: used %d, cap %d", ErrTokenLimit, tc.TokensUsed(), maxTokens)
+	}
+	return resp, nil
+}
@@ -0,0 +1,116 @@
+package limiter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/Bakery-street-project/go-ai-coder/internal/license"
+)
+
+type fakeResp struct{ tokens int }
+
+func (r fakeResp) TokensUsed() int { return r.tokens }
+
+func newTestLicense(t *testing.T, limits license.LimitsTable) *license.License {
+	t.Helper()
+	return license.Initialize(license.Options{Limits: limits})
+}
+
+func TestLimitEnforcesDailyCap(t *testing.T) {
+	lic := newTestLicense(t, license.LimitsTable{Free: license.TierLimits{MaxRuns: 2, MaxTokens: -1, MaxConcurrent: -1}})
+	runner := Limit(RunnerFunc(func(ctx context.Context, req any) (any, error) { return nil, nil }), lic)
+
+	for i := 0; i < 2; i++ {
+		if _, err := runner.Run(context.Background(), nil); err != nil {
+			t.Fatalf("run %d: unexpected error: %v", i, err)
+		}
+	}
+	if _, err := runner.Run(context.Background(), nil); !errors.Is(err, ErrDailyLimit) {
+		t.Fatalf("run 3: error = %v, want ErrDailyLimit", err)
+	}
+}
+
+func TestLimitEnforcesTokenCap(t *testing.T) {
+	lic := newTestLicense(t, license.LimitsTable{Free: license.TierLimits{MaxRuns: 10, MaxTokens: 50, MaxConcurrent: -1}})
+	runner := Limit(RunnerFunc(func(ctx context.Context, req any) (any, error) {
+		return fakeResp{tokens: 100}, nil
+	}), lic)
+
+	if _, err := runner.Run(context.Background(), nil); !errors.Is(err, ErrTokenLimit) {
+		t.Fatalf("error = %v, want ErrTokenLimit", err)
+	}
+}
+
+func TestLimitAllowsRunsUnderTokenCap(t *testing.T) {
+	lic := newTestLicense(t, license.LimitsTable{Free: license.TierLimits{MaxRuns: 10, MaxTokens: 50, MaxConcurrent: -1}})
+	runner := Limit(RunnerFunc(func(ctx context.Context, req any) (any, error) {
+		return fakeResp{tokens: 10}, nil
+	}), lic)
+
+	if _, err := runner.Run(context.Background(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestLimitEnforcesConcurrencyCap(t *testing.T) {
+	lic := newTestLicense(t, license.LimitsTable{Free: license.TierLimits{MaxRuns: 10, MaxTokens: -1, MaxConcurrent: 1}})
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	runner := Limit(RunnerFunc(func(ctx context.Context, req any) (any, error) {
+		close(started)
+		<-release
+		return nil, nil
+	}), lic)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		runner.Run(context.Background(), nil)
+	}()
+
+	<-started
+	if _, err := runner.Run(context.Background(), nil); !errors.Is(err, ErrConcurrencyLimit) {
+		t.Fatalf("error = %v, want ErrConcurrencyLimit", err)
+	}
+	close(release)
+	wg.Wait()
+}
+
+func TestMaxTokensFromContext(t *testing.T) {
+	lic := newTestLicense(t, license.LimitsTable{Free: license.TierLimits{MaxRuns: 10, MaxTokens: 42, MaxConcurrent: -1}})
+
+	var gotMax int
+	var gotOK bool
+	runner := Limit(RunnerFunc(func(ctx context.Context, req any) (any, error) {
+		gotMax, gotOK = MaxTokensFromContext(ctx)
+		return nil, nil
+	}), lic)
+
+	if _, err := runner.Run(context.Background(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !gotOK || gotMax != 42 {
+		t.Fatalf("MaxTokensFromContext = (%d, %v), want (42, true)", gotMax, gotOK)
+	}
+}
+
+func TestMaxTokensFromContextUnsetWhenUnlimited(t *testing.T) {
+	lic := newTestLicense(t, license.LimitsTable{Free: license.TierLimits{MaxRuns: 10, MaxTokens: -1, MaxConcurrent: -1}})
+
+	var gotOK bool
+	runner := Limit(RunnerFunc(func(ctx context.Context, req any) (any, error) {
+		_, gotOK = MaxTokensFromContext(ctx)
+		return nil, nil
+	}), lic)
+
+	if _, err := runner.Run(context.Background(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotOK {
+		t.Fatal("expected no max-tokens value in context for an unlimited license")
+	}
+}
@@ -0,0 +1,392 @@
+package license
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Watcher reacts to license state transitions reported by a Manager.
+type Watcher interface {
+	// OnNewLicense is called whenever the active license changes, e.g. a
+	// tier upgrade/downgrade. It is not called for no-op refreshes.
+	OnNewLicense(*License)
+	// OnStopped is called once, after the Manager's refresh loop exits.
+	OnStopped()
+}
+
+// CallbackWatcher adapts plain functions to the Watcher interface. Either
+// field may be left nil.
+type CallbackWatcher struct {
+	New     func(*License)
+	Stopped func()
+}
+
+// OnNewLicense implements Watcher.
+func (w CallbackWatcher) OnNewLicense(l *License) {
+	if w.New != nil {
+		w.New(l)
+	}
+}
+
+// OnStopped implements Watcher.
+func (w CallbackWatcher) OnStopped() {
+	if w.Stopped != nil {
+		w.Stopped()
+	}
+}
+
+// ManagerConfig configures a Manager's remote refresh behavior.
+type ManagerConfig struct {
+	// Endpoint is polled for the current license. It's expected to
+	// respond 200 OK with a JSON body of the form {"key": "<signed key>"}.
+	Endpoint string
+	// RefreshEvery is how often Endpoint is polled. Defaults to 1 hour.
+	RefreshEvery time.Duration
+	// GracePeriod is how long a previously-valid license stays active
+	// after Endpoint becomes unreachable, before downgrading to Free.
+	// Defaults to 72 hours.
+	GracePeriod time.Duration
+	// CachePath is where the last-known-good license is persisted, so a
+	// restart while offline doesn't lose it. Defaults to
+	// ~/.config/go-ai-coder/license.json. Set to "-" to disable caching.
+	CachePath string
+	// HTTPClient is used to call Endpoint. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+const disableCache = "-"
+
+func (c ManagerConfig) withDefaults() ManagerConfig {
+	if c.RefreshEvery <= 0 {
+		c.RefreshEvery = time.Hour
+	}
+	if c.GracePeriod <= 0 {
+		c.GracePeriod = 72 * time.Hour
+	}
+	if c.CachePath == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			c.CachePath = filepath.Join(home, ".config", "go-ai-coder", "license.json")
+		} else {
+			c.CachePath = disableCache
+		}
+	}
+	if c.HTTPClient == nil {
+		c.HTTPClient = http.DefaultClient
+	}
+	return c
+}
+
+// Manager periodically re-validates the active license against a remote
+// endpoint, falling back to a cached license (and eventually Free tier)
+// while offline. Callers subscribe a Watcher to react to tier transitions
+// without polling GetLicense themselves.
+type Manager struct {
+	cfg ManagerConfig
+	now func() time.Time
+
+	mu          sync.Mutex
+	current     *License
+	watchers    []Watcher
+	lastSuccess time.Time
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+}
+
+// NewManager builds a Manager around cfg. Call Start to begin the refresh
+// loop.
+func NewManager(cfg ManagerConfig) *Manager {
+	return &Manager{
+		cfg:    cfg.withDefaults(),
+		now:    time.Now,
+		stopCh: make(chan struct{}),
+		doneCh: make(chan struct{}),
+	}
+}
+
+// Subscribe registers w to be notified of future license transitions. It
+// does not fire retroactively for the license Start was seeded with.
+func (m *Manager) Subscribe(w Watcher) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.watchers = append(m.watchers, w)
+}
+
+// Start seeds the manager with the current license and begins polling
+// cfg.Endpoint in its own goroutine.
+func (m *Manager) Start(initial *License) {
+	m.mu.Lock()
+	m.current = initial
+	m.lastSuccess = m.now()
+	m.mu.Unlock()
+
+	go m.loop()
+}
+
+// Stop ends the refresh loop and blocks until it has exited. Subscribed
+// watchers receive a final OnStopped call before Stop returns.
+func (m *Manager) Stop() {
+	m.stopOnce.Do(func() { close(m.stopCh) })
+	<-m.doneCh
+}
+
+// GetLicense returns the manager's current license.
+func (m *Manager) GetLicense() *License {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.current
+}
+
+// SetLicense replaces the manager's view of the current license outside of
+// the normal refresh cycle, e.g. after a runtime Activate. It does not
+// notify watchers, since callers that bypass the refresh loop this way are
+// expected to have already surfaced the change themselves.
+func (m *Manager) SetLicense(lic *License) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.current = lic
+	m.lastSuccess = m.now()
+}
+
+func (m *Manager) loop() {
+	defer close(m.doneCh)
+	ticker := time.NewTicker(m.cfg.RefreshEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stopCh:
+			m.notifyStopped()
+			return
+		case <-ticker.C:
+			m.refresh()
+		}
+	}
+}
+
+// refresh polls the remote endpoint and applies the resulting license. If
+// the endpoint is unreachable, the current license is kept until the
+// offline grace period elapses, at which point it downgrades to Free.
+// Without a configured endpoint, it instead just watches the current
+// license for local trial expiry, so a trial started via StartTrial still
+// downgrades to Free and fires watchers without any remote to poll.
+func (m *Manager) refresh() {
+	if m.cfg.Endpoint == "" {
+		m.refreshLocal()
+		return
+	}
+
+	lic, err := m.fetchRemote()
+	if err != nil {
+		m.mu.Lock()
+		graceExpired := m.now().Sub(m.lastSuccess) > m.cfg.GracePeriod
+		m.mu.Unlock()
+		if graceExpired {
+			m.transition(freeLicense("", DefaultLimits))
+		}
+		return
+	}
+
+	m.mu.Lock()
+	m.lastSuccess = m.now()
+	m.mu.Unlock()
+	m.cache(lic)
+	m.transition(lic)
+}
+
+// refreshLocal downgrades the current license to Free if it's a trial past
+// its TrialEndsAt. It's the endpoint-less counterpart to refresh, run on
+// the same ticker so trial expiry is still detected in the background.
+func (m *Manager) refreshLocal() {
+	m.mu.Lock()
+	cur := m.current
+	now := m.now()
+	m.mu.Unlock()
+
+	if cur == nil || !trialExpiredAt(cur, now) {
+		return
+	}
+	m.transition(freeLicense("", cur.limitsSnapshot()))
+}
+
+func (m *Manager) fetchRemote() (*License, error) {
+	resp, err := m.cfg.HTTPClient.Get(m.cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("license: fetch remote: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("license: remote returned %s", resp.Status)
+	}
+
+	var body struct {
+		Key string `json:"key"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("license: decode remote response: %w", err)
+	}
+
+	claims, err := validateKey(body.Key)
+	if err != nil {
+		return nil, fmt.Errorf("license: remote key: %w", err)
+	}
+	return licenseFromClaims(body.Key, claims, DefaultLimits), nil
+}
+
+func (m *Manager) cache(lic *License) {
+	if m.cfg.CachePath == disableCache {
+		return
+	}
+	data, err := json.Marshal(lic)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(m.cfg.CachePath), 0o755); err != nil {
+		return
+	}
+	_ = os.WriteFile(m.cfg.CachePath, data, 0o600)
+}
+
+// loadCachedLicense reads a previously cached license from disk, if any.
+// limits is unexported so it doesn't survive the JSON round-trip; backfill
+// it with DefaultLimits so a later downgrade (e.g. trial expiry on a cached
+// license whose remote is unreachable) lands on real Free-tier caps instead
+// of the zero value.
+func loadCachedLicense(path string) (*License, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var lic License
+	if err := json.Unmarshal(data, &lic); err != nil {
+		return nil, err
+	}
+	lic.limits = DefaultLimits
+	return &lic, nil
+}
+
+// transition swaps in lic and notifies watchers only if the effective
+// license state changed, so watchers fire on tier transitions rather than
+// every identical refresh.
+func (m *Manager) transition(lic *License) {
+	m.mu.Lock()
+	prev := m.current
+	m.current = lic
+	watchers := append([]Watcher(nil), m.watchers...)
+	m.mu.Unlock()
+
+	if !stateChanged(prev, lic) {
+		return
+	}
+	for _, w := range watchers {
+		w.OnNewLicense(lic)
+	}
+}
+
+// notifyTrialExpiry lets the manager's watchers know that l (the
+// process-wide license) just transitioned to Free, for the case where
+// CanRun -- not the manager's own ticker -- is what caught the trial
+// expiry. l was already mutated in place, so unlike transition there's no
+// new *License to swap in: before is a snapshot taken prior to the
+// mutation, so the comparison against l's current state still reflects the
+// real change instead of comparing l against itself.
+func (m *Manager) notifyTrialExpiry(l *License, before licenseState) {
+	m.mu.Lock()
+	m.current = l
+	watchers := append([]Watcher(nil), m.watchers...)
+	m.mu.Unlock()
+
+	if sameLicenseState(before, l.snapshot()) {
+		return
+	}
+	for _, w := range watchers {
+		w.OnNewLicense(l)
+	}
+}
+
+func (m *Manager) notifyStopped() {
+	m.mu.Lock()
+	watchers := append([]Watcher(nil), m.watchers...)
+	m.mu.Unlock()
+	for _, w := range watchers {
+		w.OnStopped()
+	}
+}
+
+// licenseState is a locked-and-copied snapshot of the License fields that
+// matter for watcher-notification equality, ignoring counters that
+// legitimately change on every run (DailyRuns, LastReset). Comparing
+// snapshots instead of live *License pointers means the comparison never
+// races with a concurrent mutation of either license -- e.g. CanRun's
+// in-place trial-expiry downgrade running under l.mu while the manager's
+// ticker is mid-refresh on the same *License.
+type licenseState struct {
+	tier     Tier
+	key      string
+	seats    int
+	trial    bool
+	expires  time.Time
+	features map[string]bool
+}
+
+// snapshot takes l's licenseState under l.mu.
+func (l *License) snapshot() licenseState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.snapshotLocked()
+}
+
+// snapshotLocked is snapshot's lock-free counterpart for callers that
+// already hold l.mu.
+func (l *License) snapshotLocked() licenseState {
+	features := make(map[string]bool, len(l.Features))
+	for k, v := range l.Features {
+		features[k] = v
+	}
+	return licenseState{
+		tier:     l.Tier,
+		key:      l.Key,
+		seats:    l.Seats,
+		trial:    l.Trial,
+		expires:  l.ExpiresAt,
+		features: features,
+	}
+}
+
+// stateChanged reports whether prev and next represent different license
+// states. It compares locked snapshots rather than the live *License
+// pointers, so it's safe to call on licenses that may be mutated
+// concurrently.
+func stateChanged(prev, next *License) bool {
+	if prev == nil || next == nil {
+		return prev != next
+	}
+	return !sameLicenseState(prev.snapshot(), next.snapshot())
+}
+
+// sameLicenseState reports whether two license snapshots represent the same
+// effective entitlement.
+func sameLicenseState(a, b licenseState) bool {
+	if a.tier != b.tier || a.key != b.key || a.seats != b.seats || a.trial != b.trial {
+		return false
+	}
+	if !a.expires.Equal(b.expires) {
+		return false
+	}
+	if len(a.features) != len(b.features) {
+		return false
+	}
+	for k, v := range a.features {
+		if b.features[k] != v {
+			return false
+		}
+	}
+	return true
+}
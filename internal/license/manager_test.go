@@ -0,0 +1,152 @@
+package license
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually-advanced time source for deterministic Manager tests.
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func newFakeClock(t time.Time) *fakeClock { return &fakeClock{t: t} }
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+// recordingWatcher captures every transition it's notified of.
+type recordingWatcher struct {
+	mu      sync.Mutex
+	tiers   []Tier
+	stopped int
+}
+
+func (w *recordingWatcher) OnNewLicense(l *License) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.tiers = append(w.tiers, l.Tier)
+}
+
+func (w *recordingWatcher) OnStopped() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.stopped++
+}
+
+func remoteKeyServer(t *testing.T, key string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"key": %q}`, key)
+	}))
+}
+
+func TestManagerRefreshAppliesRemoteLicense(t *testing.T) {
+	key := signedTestKey(t, Claims{Tier: ProTier, ExpiresAt: time.Now().Add(30 * 24 * time.Hour)})
+	srv := remoteKeyServer(t, key)
+	defer srv.Close()
+
+	m := NewManager(ManagerConfig{Endpoint: srv.URL, CachePath: "-"})
+	watcher := &recordingWatcher{}
+	m.Subscribe(watcher)
+	m.Start(freeLicense("", DefaultLimits))
+	defer m.Stop()
+
+	m.refresh()
+
+	if got := m.GetLicense().Tier; got != ProTier {
+		t.Fatalf("GetLicense().Tier = %v, want ProTier", got)
+	}
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+	if len(watcher.tiers) != 1 || watcher.tiers[0] != ProTier {
+		t.Fatalf("watcher.tiers = %v, want [ProTier]", watcher.tiers)
+	}
+}
+
+func TestManagerCoalescesIdenticalRefreshes(t *testing.T) {
+	key := signedTestKey(t, Claims{Tier: ProTier, ExpiresAt: time.Now().Add(30 * 24 * time.Hour)})
+	srv := remoteKeyServer(t, key)
+	defer srv.Close()
+
+	m := NewManager(ManagerConfig{Endpoint: srv.URL, CachePath: "-"})
+	watcher := &recordingWatcher{}
+	m.Subscribe(watcher)
+	m.Start(freeLicense("", DefaultLimits))
+	defer m.Stop()
+
+	m.refresh()
+	m.refresh()
+	m.refresh()
+
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+	if len(watcher.tiers) != 1 {
+		t.Fatalf("expected exactly one transition, got %d: %v", len(watcher.tiers), watcher.tiers)
+	}
+}
+
+func TestManagerOfflineGracePeriod(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	clock := newFakeClock(time.Now())
+	m := NewManager(ManagerConfig{Endpoint: srv.URL, CachePath: "-", GracePeriod: time.Hour})
+	m.now = clock.Now
+	watcher := &recordingWatcher{}
+	m.Subscribe(watcher)
+	m.Start(&License{Tier: ProTier, MaxRuns: ProMaxRunsPerDay})
+	defer m.Stop()
+
+	clock.Advance(30 * time.Minute)
+	m.refresh()
+	if got := m.GetLicense().Tier; got != ProTier {
+		t.Fatalf("within grace period: Tier = %v, want ProTier", got)
+	}
+
+	clock.Advance(45 * time.Minute)
+	m.refresh()
+	if got := m.GetLicense().Tier; got != FreeTier {
+		t.Fatalf("past grace period: Tier = %v, want FreeTier", got)
+	}
+
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+	if len(watcher.tiers) != 1 || watcher.tiers[0] != FreeTier {
+		t.Fatalf("watcher.tiers = %v, want [FreeTier]", watcher.tiers)
+	}
+}
+
+func TestManagerStopNotifiesWatchers(t *testing.T) {
+	srv := remoteKeyServer(t, signedTestKey(t, Claims{Tier: ProTier, ExpiresAt: time.Now().Add(24 * time.Hour)}))
+	defer srv.Close()
+
+	m := NewManager(ManagerConfig{Endpoint: srv.URL, RefreshEvery: time.Hour, CachePath: "-"})
+	watcher := &recordingWatcher{}
+	m.Subscribe(watcher)
+	m.Start(freeLicense("", DefaultLimits))
+
+	m.Stop()
+
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+	if watcher.stopped != 1 {
+		t.Fatalf("watcher.stopped = %d, want 1", watcher.stopped)
+	}
+}
@@ -0,0 +1,106 @@
+package license
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// TrialDuration is how long a trial started via StartTrial grants
+// Pro-equivalent access before automatically downgrading to Free.
+const TrialDuration = 14 * 24 * time.Hour
+
+// StartTrial begins a 14-day Pro-equivalent trial for email and makes it
+// the active license. A real deployment would hit a licensing server to
+// register the trial against email and mint a signed token; this
+// implementation mints the trial locally since no such server exists yet.
+func StartTrial(email string) error {
+	if email == "" {
+		return fmt.Errorf("license: trial requires an email address")
+	}
+
+	limits := DefaultLimits
+	ents := DefaultEntitlements(ProTier, limits)
+	now := time.Now()
+	lic := &License{
+		Tier:          ProTier,
+		LastReset:     now,
+		Trial:         true,
+		TrialEndsAt:   now.Add(TrialDuration),
+		MaxRuns:       ents[EntitlementMaxRuns],
+		MaxTokens:     ents[EntitlementMaxTokens],
+		MaxConcurrent: ents[EntitlementMaxConcurrent],
+		Entitlements:  ents,
+		limits:        limits,
+	}
+	setCurrentLicense(lic)
+	if mgr := getLicenseManager(); mgr != nil {
+		mgr.SetLicense(lic)
+	}
+	fmt.Printf("✅ Pro trial started for %s - 14 days, 100 runs/day, 10K tokens/run\n", email)
+	return nil
+}
+
+// Activate validates key and makes the resulting license active
+// immediately, without requiring a process restart. It's the runtime
+// counterpart to LICENSE_KEY: a user who purchases mid-session can run
+// this instead of re-launching.
+func Activate(key string) error {
+	claims, err := validateKey(key)
+	if err != nil {
+		return fmt.Errorf("license: activate: %w", err)
+	}
+
+	lic := licenseFromClaims(key, claims, DefaultLimits)
+	setCurrentLicense(lic)
+	if mgr := getLicenseManager(); mgr != nil {
+		mgr.SetLicense(lic)
+	}
+
+	switch lic.Tier {
+	case EnterpriseTier:
+		fmt.Println("✅ Enterprise License activated - Unlimited usage")
+	case ProTier:
+		fmt.Println("✅ Pro License activated - 100 runs/day, 10K tokens/run")
+	}
+	return nil
+}
+
+// downgradeToFreeLocked resets l to Free-tier limits in place. Callers
+// must hold l.mu.
+func (l *License) downgradeToFreeLocked() {
+	ents := DefaultEntitlements(FreeTier, l.limits)
+	l.Tier = FreeTier
+	l.Trial = false
+	l.TrialEndsAt = time.Time{}
+	l.ExpiresAt = time.Time{}
+	l.MaxRuns = ents[EntitlementMaxRuns]
+	l.MaxTokens = ents[EntitlementMaxTokens]
+	l.MaxConcurrent = ents[EntitlementMaxConcurrent]
+	l.Entitlements = ents
+}
+
+// limitsSnapshot returns the LimitsTable l was built from.
+func (l *License) limitsSnapshot() LimitsTable {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limits
+}
+
+// trialExpiredAt reports whether l is a trial whose TrialEndsAt has passed
+// as of now.
+func trialExpiredAt(l *License, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.Trial && !l.TrialEndsAt.IsZero() && now.After(l.TrialEndsAt)
+}
+
+// daysLeft renders the whole number of days remaining until t, rounding
+// up so "a few hours left" still reads as "1 days" rather than "0 days".
+func daysLeft(t time.Time) string {
+	days := int(math.Ceil(time.Until(t).Hours() / 24))
+	if days < 0 {
+		days = 0
+	}
+	return fmt.Sprintf("%d days", days)
+}
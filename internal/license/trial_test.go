@@ -0,0 +1,168 @@
+package license
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStartTrial(t *testing.T) {
+	resetLicense()
+	t.Cleanup(Stop)
+
+	if err := StartTrial("dev@example.com"); err != nil {
+		t.Fatalf("StartTrial: %v", err)
+	}
+
+	lic := GetLicense()
+	if lic.Tier != ProTier {
+		t.Fatalf("Tier = %v, want ProTier", lic.Tier)
+	}
+	if !lic.Trial {
+		t.Fatal("expected Trial to be true")
+	}
+	if got := time.Until(lic.TrialEndsAt); got < 13*24*time.Hour || got > TrialDuration {
+		t.Fatalf("TrialEndsAt = %v from now, want ~14 days", got)
+	}
+	if status := lic.GetStatus(); !strings.Contains(status, "Trial") || !strings.Contains(status, "days left") {
+		t.Errorf("GetStatus() = %q, want it to mention the trial countdown", status)
+	}
+}
+
+func TestStartTrialRequiresEmail(t *testing.T) {
+	if err := StartTrial(""); err == nil {
+		t.Fatal("expected an error for an empty email")
+	}
+}
+
+func TestTrialExpiryDowngradesAndNotifiesWatchers(t *testing.T) {
+	clock := newFakeClock(time.Now())
+	trial := &License{
+		Tier:        ProTier,
+		Trial:       true,
+		TrialEndsAt: clock.Now().Add(time.Hour),
+		MaxRuns:     ProMaxRunsPerDay,
+		MaxTokens:   ProMaxTokensPerRun,
+		limits:      DefaultLimits,
+	}
+
+	m := NewManager(ManagerConfig{CachePath: "-"})
+	m.now = clock.Now
+	watcher := &recordingWatcher{}
+	m.Subscribe(watcher)
+	m.Start(trial)
+	defer m.Stop()
+
+	m.refresh()
+	if got := m.GetLicense().Tier; got != ProTier {
+		t.Fatalf("before expiry: Tier = %v, want ProTier", got)
+	}
+
+	clock.Advance(2 * time.Hour)
+	m.refresh()
+
+	if got := m.GetLicense(); got.Tier != FreeTier || got.Trial {
+		t.Fatalf("after expiry: Tier = %v, Trial = %v, want FreeTier, false", got.Tier, got.Trial)
+	}
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+	if len(watcher.tiers) != 1 || watcher.tiers[0] != FreeTier {
+		t.Fatalf("watcher.tiers = %v, want [FreeTier]", watcher.tiers)
+	}
+}
+
+func TestCanRunDowngradesExpiredTrialAndNotifiesWatchers(t *testing.T) {
+	resetLicense()
+	t.Cleanup(Stop)
+
+	trial := &License{
+		Tier:        ProTier,
+		Trial:       true,
+		TrialEndsAt: time.Now().Add(-time.Minute),
+		MaxRuns:     ProMaxRunsPerDay,
+		MaxTokens:   ProMaxTokensPerRun,
+		limits:      DefaultLimits,
+	}
+
+	licenseManager = NewManager(ManagerConfig{CachePath: "-", RefreshEvery: time.Hour})
+	watcher := &recordingWatcher{}
+	licenseManager.Subscribe(watcher)
+	licenseManager.Start(trial)
+
+	if ok, _ := trial.CanRun(); !ok {
+		t.Fatal("expected the run right after expiry to still be allowed under Free limits")
+	}
+	if trial.Tier != FreeTier || trial.Trial {
+		t.Fatalf("Tier = %v, Trial = %v, want FreeTier, false", trial.Tier, trial.Trial)
+	}
+
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+	if len(watcher.tiers) != 1 || watcher.tiers[0] != FreeTier {
+		t.Fatalf("watcher.tiers = %v, want [FreeTier]; CanRun's downgrade must notify watchers like the manager's own ticker does", watcher.tiers)
+	}
+}
+
+func TestStartTrialUpdatesLiveManager(t *testing.T) {
+	resetLicense()
+	t.Cleanup(Stop)
+
+	if lic := Initialize(); lic.Tier != FreeTier {
+		t.Fatalf("expected Free tier before trial, got %v", lic.Tier)
+	}
+
+	mgr := getLicenseManager()
+	if mgr == nil {
+		t.Fatal("Initialize should have started a manager")
+	}
+	watcher := &recordingWatcher{}
+	mgr.Subscribe(watcher)
+
+	if err := StartTrial("dev@example.com"); err != nil {
+		t.Fatalf("StartTrial: %v", err)
+	}
+
+	// The manager's own view must be the just-started trial, not the
+	// stale pre-trial Free license -- otherwise its ticker never sees
+	// the trial at all, and a configured LICENSE_ENDPOINT's next refresh
+	// would diff the incoming remote license against that stale
+	// snapshot instead.
+	if got := mgr.GetLicense(); !got.Trial || got.Tier != ProTier {
+		t.Fatalf("manager.GetLicense() = %+v, want the active trial", got)
+	}
+
+	// Force expiry and let the manager's own refresh (not CanRun) catch it.
+	mgr.GetLicense().TrialEndsAt = time.Now().Add(-time.Minute)
+	mgr.refresh()
+
+	if got := mgr.GetLicense(); got.Trial || got.Tier != FreeTier {
+		t.Fatalf("after expiry: Tier = %v, Trial = %v, want FreeTier, false", got.Tier, got.Trial)
+	}
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+	if len(watcher.tiers) != 1 || watcher.tiers[0] != FreeTier {
+		t.Fatalf("watcher.tiers = %v, want [FreeTier] fired by the manager's own ticker", watcher.tiers)
+	}
+}
+
+func TestActivateReplacesActiveFreeLicense(t *testing.T) {
+	resetLicense()
+	t.Cleanup(Stop)
+
+	if lic := Initialize(); lic.Tier != FreeTier {
+		t.Fatalf("expected Free tier before activation, got %v", lic.Tier)
+	}
+
+	key := signedTestKey(t, Claims{Tier: ProTier, Seats: 3, ExpiresAt: time.Now().Add(30 * 24 * time.Hour)})
+	if err := Activate(key); err != nil {
+		t.Fatalf("Activate: %v", err)
+	}
+
+	lic := GetLicense()
+	if lic.Tier != ProTier {
+		t.Fatalf("Tier = %v, want ProTier", lic.Tier)
+	}
+	if lic.Seats != 3 {
+		t.Errorf("Seats = %d, want 3", lic.Seats)
+	}
+}